@@ -0,0 +1,187 @@
+package GoRest
+
+import (
+    "crypto/md5"
+    "crypto/rand"
+    "encoding/hex"
+    "fmt"
+    "net/http"
+    "strings"
+    "sync"
+
+    "golang.org/x/oauth2"
+)
+
+// Authenticator applies credentials to an outgoing request. It is called after headers
+// and query params have been set, so it sees the final request about to be sent.
+type Authenticator interface {
+    Apply(req *http.Request) error
+}
+
+// ChallengeAuthenticator is implemented by Authenticators that need to inspect a 401
+// response before they can produce credentials (e.g. Digest auth). When rc.auth
+// implements this interface, doAttempt replays the request once after a successful
+// Challenge.
+type ChallengeAuthenticator interface {
+    Authenticator
+    // Challenge inspects a 401 response and caches whatever state it needs to
+    // authenticate. It returns true if the caller should rebuild and resend the request.
+    Challenge(res *http.Response) (bool, error)
+}
+
+// AuthProvider sets a custom Authenticator, overriding BasicAuth/BearerToken/TokenSource.
+func (rc RestClient) AuthProvider(auth Authenticator) RestClient {
+    newRc := rc.clone()
+    newRc.auth = auth
+    return newRc
+}
+
+// BasicAuth authenticates every request with HTTP Basic credentials.
+func (rc RestClient) BasicAuth(user, pass string) RestClient {
+    return rc.AuthProvider(basicAuth{user: user, pass: pass})
+}
+
+// BearerToken authenticates every request with a static `Authorization: Bearer <token>`
+// header.
+func (rc RestClient) BearerToken(token string) RestClient {
+    return rc.AuthProvider(bearerAuth{token: token})
+}
+
+// TokenSource authenticates every request with a token pulled from an oauth2.TokenSource,
+// so a refreshing source can hand out a fresh access token per call.
+func (rc RestClient) TokenSource(source oauth2.TokenSource) RestClient {
+    return rc.AuthProvider(tokenSourceAuth{source: source})
+}
+
+type basicAuth struct {
+    user string
+    pass string
+}
+
+func (b basicAuth) Apply(req *http.Request) error {
+    req.SetBasicAuth(b.user, b.pass)
+    return nil
+}
+
+type bearerAuth struct {
+    token string
+}
+
+func (b bearerAuth) Apply(req *http.Request) error {
+    req.Header.Set("Authorization", "Bearer "+b.token)
+    return nil
+}
+
+type tokenSourceAuth struct {
+    source oauth2.TokenSource
+}
+
+func (t tokenSourceAuth) Apply(req *http.Request) error {
+    token, err := t.source.Token()
+    if err != nil { return err }
+    token.SetAuthHeader(req)
+    return nil
+}
+
+// DigestAuth implements RFC 7616 Digest authentication. The first request on a client
+// goes out unauthenticated; once a 401 with a WWW-Authenticate: Digest challenge comes
+// back, Challenge caches the realm/nonce/qop and doAttempt replays the request with Apply
+// now able to compute a response digest. A single DigestAuth instance should be reused
+// across requests to the same client so the cached nonce/nc counter survive.
+type DigestAuth struct {
+    Username string
+    Password string
+
+    mu        sync.Mutex
+    realm     string
+    nonce     string
+    opaque    string
+    qop       string
+    nc        int
+}
+
+// NewDigestAuth creates a DigestAuth ready to be passed to AuthProvider.
+func NewDigestAuth(username, password string) *DigestAuth {
+    return &DigestAuth{Username: username, Password: password}
+}
+
+func (d *DigestAuth) Apply(req *http.Request) error {
+    d.mu.Lock()
+    defer d.mu.Unlock()
+
+    if d.nonce == "" {
+        // No challenge observed yet; send the request bare and let Challenge prime us.
+        return nil
+    }
+
+    d.nc++
+    cnonce, err := randomHex(8)
+    if err != nil { return err }
+
+    ha1 := md5Hex(fmt.Sprintf("%s:%s:%s", d.Username, d.realm, d.Password))
+    ha2 := md5Hex(fmt.Sprintf("%s:%s", req.Method, req.URL.RequestURI()))
+
+    var response string
+    if d.qop != "" {
+        response = md5Hex(fmt.Sprintf("%s:%s:%08x:%s:%s:%s", ha1, d.nonce, d.nc, cnonce, d.qop, ha2))
+    } else {
+        response = md5Hex(fmt.Sprintf("%s:%s:%s", ha1, d.nonce, ha2))
+    }
+
+    header := fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`,
+        d.Username, d.realm, d.nonce, req.URL.RequestURI(), response)
+    if d.qop != "" {
+        header += fmt.Sprintf(`, qop=%s, nc=%08x, cnonce="%s"`, d.qop, d.nc, cnonce)
+    }
+    if d.opaque != "" {
+        header += fmt.Sprintf(`, opaque="%s"`, d.opaque)
+    }
+
+    req.Header.Set("Authorization", header)
+    return nil
+}
+
+func (d *DigestAuth) Challenge(res *http.Response) (bool, error) {
+    header := res.Header.Get("WWW-Authenticate")
+    if !strings.HasPrefix(header, "Digest ") { return false, nil }
+
+    params := parseDigestParams(strings.TrimPrefix(header, "Digest "))
+
+    d.mu.Lock()
+    d.realm = params["realm"]
+    d.nonce = params["nonce"]
+    d.opaque = params["opaque"]
+    d.qop = firstToken(params["qop"], ",")
+    d.nc = 0
+    d.mu.Unlock()
+
+    return d.nonce != "", nil
+}
+
+// parseDigestParams splits the comma-separated key="value" (or key=value) pairs out of
+// a WWW-Authenticate: Digest header, with the "Digest " prefix already stripped.
+func parseDigestParams(s string) map[string]string {
+    params := make(map[string]string)
+    for _, part := range strings.Split(s, ",") {
+        kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+        if len(kv) != 2 { continue }
+        params[kv[0]] = strings.Trim(kv[1], `"`)
+    }
+    return params
+}
+
+// firstToken returns the first sep-delimited token in s, trimmed of whitespace.
+func firstToken(s, sep string) string {
+    return strings.TrimSpace(strings.SplitN(s, sep, 2)[0])
+}
+
+func md5Hex(s string) string {
+    sum := md5.Sum([]byte(s))
+    return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+    b := make([]byte, n)
+    if _, err := rand.Read(b); err != nil { return "", err }
+    return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,80 @@
+package GoRest
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestDigestAuthRoundTrip(t *testing.T) {
+    const username = "alice"
+    const password = "hunter2"
+    const realm = "test-realm"
+    const nonce = "abc123nonce"
+    const qop = "auth"
+
+    var challenges, authenticated int
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        authHeader := r.Header.Get("Authorization")
+        if !strings.HasPrefix(authHeader, "Digest ") {
+            challenges++
+            w.Header().Set("WWW-Authenticate", `Digest realm="`+realm+`", nonce="`+nonce+`", qop="`+qop+`"`)
+            w.WriteHeader(http.StatusUnauthorized)
+            return
+        }
+
+        params := parseDigestParams(strings.TrimPrefix(authHeader, "Digest "))
+        ha1 := md5Hex(username + ":" + realm + ":" + password)
+        ha2 := md5Hex(r.Method + ":" + params["uri"])
+        expected := md5Hex(ha1 + ":" + nonce + ":" + params["nc"] + ":" + params["cnonce"] + ":" + qop + ":" + ha2)
+
+        if params["username"] != username || params["response"] != expected {
+            http.Error(w, "bad digest credentials", http.StatusForbidden)
+            return
+        }
+
+        authenticated++
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer server.Close()
+
+    client := MakeClient(server.URL).AuthProvider(NewDigestAuth(username, password))
+    if err := client.Get(); err != nil {
+        t.Fatalf("expected digest round trip to succeed, got %v", err)
+    }
+
+    if challenges != 1 {
+        t.Fatalf("expected exactly one 401 challenge, got %d", challenges)
+    }
+    if authenticated != 1 {
+        t.Fatalf("expected exactly one authenticated request, got %d", authenticated)
+    }
+}
+
+func TestUnhandledChallengeReturnsRealResponse(t *testing.T) {
+    type errorBody struct {
+        Error string `json:"error"`
+    }
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        // A Basic challenge is not something DigestAuth understands, so Challenge should
+        // report handled == false and leave this response untouched.
+        w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusUnauthorized)
+        w.Write([]byte(`{"error":"unauthorized"}`))
+    }))
+    defer server.Close()
+
+    client := MakeClient(server.URL).AuthProvider(NewDigestAuth("alice", "hunter2"))
+
+    var out errorBody
+    err := client.Get(&out)
+    if err != nil {
+        t.Fatalf("expected the real 401 body to be decoded without error, got %v", err)
+    }
+    if out.Error != "unauthorized" {
+        t.Fatalf("expected decoded body to survive the unhandled challenge, got %+v", out)
+    }
+}
@@ -0,0 +1,116 @@
+package GoRest
+
+import (
+    "bytes"
+    "encoding/json"
+    "encoding/xml"
+    "errors"
+    "fmt"
+    "io"
+    "io/ioutil"
+    "net/url"
+    "strings"
+)
+
+// Codec marshals request bodies to the wire and unmarshals response bodies back into Go
+// values for a single MediaType. RestClient keeps a registry of these keyed by MediaType
+// so callers can swap in msgpack, a faster JSON library, etc. without forking the module.
+type Codec interface {
+    Encode(v interface{}) (io.Reader, string, error)
+    Decode(r io.Reader, v interface{}) error
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(v interface{}) (io.Reader, string, error) {
+    data, err := json.Marshal(v)
+    if err != nil { return nil, "", err }
+    return bytes.NewReader(data), ApplicationJSON.String(), nil
+}
+
+func (jsonCodec) Decode(r io.Reader, v interface{}) error {
+    return json.NewDecoder(r).Decode(v)
+}
+
+type xmlCodec struct{}
+
+func (xmlCodec) Encode(v interface{}) (io.Reader, string, error) {
+    data, err := xml.Marshal(v)
+    if err != nil { return nil, "", err }
+    return bytes.NewReader(data), ApplicationXML.String(), nil
+}
+
+func (xmlCodec) Decode(r io.Reader, v interface{}) error {
+    return xml.NewDecoder(r).Decode(v)
+}
+
+type formCodec struct{}
+
+func (formCodec) Encode(v interface{}) (io.Reader, string, error) {
+    values, ok := v.(url.Values)
+    if !ok { return nil, "", fmt.Errorf("form codec requires url.Values, got %T", v) }
+    return strings.NewReader(values.Encode()), ApplicationForm.String(), nil
+}
+
+func (formCodec) Decode(r io.Reader, v interface{}) error {
+    return errors.New("form codec does not support decoding responses")
+}
+
+// defaultCodecs returns the registry every RestClient starts with.
+func defaultCodecs() map[MediaType]Codec {
+    return map[MediaType]Codec{
+        ApplicationJSON: jsonCodec{},
+        ApplicationXML:  xmlCodec{},
+        ApplicationForm: formCodec{},
+    }
+}
+
+// RegisterCodec installs codec as the encoder/decoder for mt, overriding the built-in
+// JSON/XML/form codecs if mt collides with one of them.
+func (rc RestClient) RegisterCodec(mt MediaType, codec Codec) RestClient {
+    newRc := rc.clone()
+    newRc.codecs[mt] = codec
+    return newRc
+}
+
+// codecFor looks up the codec registered for mt.
+func (rc RestClient) codecFor(mt MediaType) (Codec, error) {
+    if codec, ok := rc.codecs[mt]; ok { return codec, nil }
+    return nil, fmt.Errorf("no codec registered for media type %q", mt.String())
+}
+
+// encode runs v through the codec registered for mt and reads the result into memory so
+// it can be replayed across retry attempts.
+func (rc RestClient) encode(mt MediaType, v interface{}) ([]byte, error) {
+    codec, err := rc.codecFor(mt)
+    if err != nil { return nil, err }
+
+    r, _, err := codec.Encode(v)
+    if err != nil { return nil, err }
+
+    return ioutil.ReadAll(r)
+}
+
+// PostJSON encodes v as JSON and POSTs it, decoding the response into out via the accept
+// codec.
+func (rc RestClient) PostJSON(v interface{}, out ...interface{}) error {
+    data, err := rc.encode(ApplicationJSON, v)
+    if err != nil { return err }
+    return rc.ContentType(ApplicationJSON).Post(data, out...)
+}
+
+// PostXML encodes v as XML and POSTs it, decoding the response into out via the accept
+// codec.
+func (rc RestClient) PostXML(v interface{}, out ...interface{}) error {
+    data, err := rc.encode(ApplicationXML, v)
+    if err != nil { return err }
+    return rc.ContentType(ApplicationXML).Post(data, out...)
+}
+
+// PostForm url-encodes values as application/x-www-form-urlencoded and POSTs it, decoding
+// the response into out via the accept codec.
+func (rc RestClient) PostForm(values url.Values, out ...interface{}) error {
+    data, err := rc.encode(ApplicationForm, values)
+    if err != nil { return err }
+    return rc.ContentType(ApplicationForm).Post(data, out...)
+}
@@ -0,0 +1,72 @@
+package GoRest
+
+import (
+    "crypto/tls"
+    "net/http"
+    "net/http/httptrace"
+    "time"
+)
+
+// RequestMiddleware inspects or mutates an outgoing *http.Request before it is sent. A
+// non-nil error aborts the request and is returned to the caller.
+type RequestMiddleware func(*http.Request) error
+
+// ResponseMiddleware inspects a received *http.Response before its body is read into the
+// caller's entities. A non-nil error aborts the request and is returned to the caller.
+type ResponseMiddleware func(*http.Response) error
+
+// TraceInfo holds the httptrace.ClientTrace timestamps captured by Trace. Durations are
+// zero if the corresponding event never fired (e.g. TLS timings on a plain HTTP request).
+type TraceInfo struct {
+    DNSStart             time.Time
+    DNSDone              time.Time
+    ConnectStart         time.Time
+    ConnectDone          time.Time
+    TLSStart             time.Time
+    TLSDone              time.Time
+    GotFirstResponseByte time.Time
+}
+
+// DNSLookup returns how long DNS resolution took.
+func (t TraceInfo) DNSLookup() time.Duration {
+    return t.DNSDone.Sub(t.DNSStart)
+}
+
+// TCPConnection returns how long establishing the TCP connection took.
+func (t TraceInfo) TCPConnection() time.Duration {
+    return t.ConnectDone.Sub(t.ConnectStart)
+}
+
+// TLSHandshake returns how long the TLS handshake took. It is zero for plain HTTP.
+func (t TraceInfo) TLSHandshake() time.Duration {
+    return t.TLSDone.Sub(t.TLSStart)
+}
+
+// TimeToFirstByte returns how long the server took to respond after the connection was
+// established.
+func (t TraceInfo) TimeToFirstByte() time.Duration {
+    if t.ConnectDone.IsZero() || t.GotFirstResponseByte.IsZero() { return 0 }
+    return t.GotFirstResponseByte.Sub(t.ConnectDone)
+}
+
+// Trace returns a RequestMiddleware that wires an httptrace.ClientTrace into the request's
+// context, recording DNS, connect, TLS, and time-to-first-byte timings into info. Pass the
+// same *TraceInfo to OnBeforeRequest and inspect it after the call returns:
+//
+//	info := &GoRest.TraceInfo{}
+//	err := client.OnBeforeRequest(GoRest.Trace(info)).Get(&out)
+func Trace(info *TraceInfo) RequestMiddleware {
+    return func(req *http.Request) error {
+        trace := &httptrace.ClientTrace{
+            DNSStart:             func(httptrace.DNSStartInfo) { info.DNSStart = time.Now() },
+            DNSDone:              func(httptrace.DNSDoneInfo) { info.DNSDone = time.Now() },
+            ConnectStart:         func(string, string) { info.ConnectStart = time.Now() },
+            ConnectDone:          func(string, string, error) { info.ConnectDone = time.Now() },
+            TLSHandshakeStart:    func() { info.TLSStart = time.Now() },
+            TLSHandshakeDone:     func(tls.ConnectionState, error) { info.TLSDone = time.Now() },
+            GotFirstResponseByte: func() { info.GotFirstResponseByte = time.Now() },
+        }
+        *req = *req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+        return nil
+    }
+}
@@ -0,0 +1,158 @@
+package GoRest
+
+import (
+    "io"
+    "mime/multipart"
+    "os"
+    "path/filepath"
+)
+
+type multipartPart struct {
+    field    string
+    value    string
+    isFile   bool
+    filename string
+    file     io.Reader
+}
+
+// MultipartBuilder constructs a multipart/form-data request body. Unlike RestClient's
+// immutable builder, it's mutated in place as fields and files are added, then finalized
+// once with Build. The first error encountered is sticky: later calls become no-ops and
+// Build returns it.
+//
+// Build streams the encoded body through an io.Pipe rather than buffering it, so
+// AddFileFromDisk on a large file doesn't load the whole thing into memory before the
+// request is sent.
+type MultipartBuilder struct {
+    parts []multipartPart
+    err   error
+}
+
+// Multipart starts a new multipart/form-data body.
+func (rc RestClient) Multipart() *MultipartBuilder {
+    return &MultipartBuilder{}
+}
+
+// AddField writes a plain form field.
+func (m *MultipartBuilder) AddField(name, value string) *MultipartBuilder {
+    if m.err != nil { return m }
+    m.parts = append(m.parts, multipartPart{field: name, value: value})
+    return m
+}
+
+// AddFile streams r's contents as a file part under fieldName, reported to the server as
+// filename, when the body built by Build is sent.
+func (m *MultipartBuilder) AddFile(fieldName, filename string, r io.Reader) *MultipartBuilder {
+    if m.err != nil { return m }
+    m.parts = append(m.parts, multipartPart{field: fieldName, isFile: true, filename: filename, file: r})
+    return m
+}
+
+// AddFileFromDisk opens path and streams it as a file part under field, using the file's
+// base name as the reported filename. The file is opened immediately (so a bad path is
+// reported right away) but read lazily when the body is sent.
+func (m *MultipartBuilder) AddFileFromDisk(field, path string) *MultipartBuilder {
+    if m.err != nil { return m }
+
+    f, err := os.Open(path)
+    if err != nil { m.err = err; return m }
+
+    return m.AddFile(field, filepath.Base(path), f)
+}
+
+// Build returns the body's Content-Type (including boundary) and a streaming io.Reader of
+// its encoded bytes, ready to be passed to RestClient.ContentType(...).Stream(...).
+//
+// When every file part is seekable, the returned reader also reports its exact encoded
+// length via Size() int64, which Stream uses to set Content-Length instead of falling back
+// to chunked transfer encoding. Computing that length only costs a Seek per file, not a
+// read of its contents, so the streaming behavior above is unaffected either way.
+func (m *MultipartBuilder) Build() (MediaType, io.Reader, error) {
+    if m.err != nil { return "", nil, m.err }
+
+    boundary := multipart.NewWriter(io.Discard).Boundary()
+
+    pr, pw := io.Pipe()
+    writer := multipart.NewWriter(pw)
+    if err := writer.SetBoundary(boundary); err != nil { return "", nil, err }
+    contentType := MediaType(writer.FormDataContentType())
+
+    go func() {
+        err := m.writeParts(writer)
+        if cerr := writer.Close(); err == nil { err = cerr }
+        pw.CloseWithError(err)
+    }()
+
+    if size, ok := m.seekableSize(boundary); ok {
+        return contentType, &sizedReader{Reader: pr, size: size}, nil
+    }
+    return contentType, pr, nil
+}
+
+// seekableSize reports the exact encoded body length for boundary when every file part is
+// seekable, without reading any file's contents: each file's size comes from Seek and the
+// surrounding multipart header/boundary bytes come from a throwaway pass that discards
+// everything it writes. It returns false when any file part isn't seekable, since then the
+// encoded length can only be known by reading the whole body.
+func (m *MultipartBuilder) seekableSize(boundary string) (int64, bool) {
+    var counted countingWriter
+    writer := multipart.NewWriter(&counted)
+    if err := writer.SetBoundary(boundary); err != nil { return 0, false }
+
+    var fileBytes int64
+    for _, p := range m.parts {
+        if !p.isFile {
+            if err := writer.WriteField(p.field, p.value); err != nil { return 0, false }
+            continue
+        }
+
+        seeker, ok := p.file.(io.Seeker)
+        if !ok { return 0, false }
+
+        size, err := seeker.Seek(0, io.SeekEnd)
+        if err != nil { return 0, false }
+        if _, err := seeker.Seek(0, io.SeekStart); err != nil { return 0, false }
+        fileBytes += size
+
+        if _, err := writer.CreateFormFile(p.field, p.filename); err != nil { return 0, false }
+    }
+    if err := writer.Close(); err != nil { return 0, false }
+
+    return int64(counted) + fileBytes, true
+}
+
+// countingWriter discards everything written to it, keeping only a running byte count.
+type countingWriter int64
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+    *c += countingWriter(len(p))
+    return len(p), nil
+}
+
+// sizedReader pairs a streaming io.Reader with a precomputed length, letting callers like
+// Stream set Content-Length up front instead of falling back to chunked encoding.
+type sizedReader struct {
+    io.Reader
+    size int64
+}
+
+func (s *sizedReader) Size() int64 { return s.size }
+
+// writeParts writes every recorded field and file into writer, in the order they were
+// added, closing each file part's reader (if it implements io.Closer) as it finishes.
+func (m *MultipartBuilder) writeParts(writer *multipart.Writer) error {
+    for _, p := range m.parts {
+        if !p.isFile {
+            if err := writer.WriteField(p.field, p.value); err != nil { return err }
+            continue
+        }
+
+        part, err := writer.CreateFormFile(p.field, p.filename)
+        if err != nil { return err }
+
+        _, err = io.Copy(part, p.file)
+        if closer, ok := p.file.(io.Closer); ok { closer.Close() }
+        if err != nil { return err }
+    }
+    return nil
+}
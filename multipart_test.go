@@ -0,0 +1,91 @@
+package GoRest
+
+import (
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestMultipartBuilderStreamsWithoutBuffering(t *testing.T) {
+    var gotField string
+    var gotFileContents string
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if err := r.ParseMultipartForm(1 << 20); err != nil {
+            t.Errorf("failed to parse multipart form: %v", err)
+            return
+        }
+        gotField = r.FormValue("name")
+
+        file, _, err := r.FormFile("upload")
+        if err != nil { t.Errorf("failed to read uploaded file: %v", err); return }
+        defer file.Close()
+
+        data, _ := io.ReadAll(file)
+        gotFileContents = string(data)
+    }))
+    defer server.Close()
+
+    ct, body, err := MakeClient(server.URL).Multipart().
+        AddField("name", "example").
+        AddFile("upload", "hello.txt", nonSeekableReader{strings.NewReader("hello multipart")}).
+        Build()
+    if err != nil { t.Fatalf("Build failed: %v", err) }
+
+    if _, ok := body.(*io.PipeReader); !ok {
+        t.Fatalf("expected Build to return a streaming *io.PipeReader, got %T", body)
+    }
+
+    client := MakeClient(server.URL).ContentType(ct)
+    if _, _, err := client.Stream("POST", body); err != nil {
+        t.Fatalf("Stream failed: %v", err)
+    }
+
+    if gotField != "example" {
+        t.Fatalf("expected field %q, got %q", "example", gotField)
+    }
+    if gotFileContents != "hello multipart" {
+        t.Fatalf("expected file contents %q, got %q", "hello multipart", gotFileContents)
+    }
+}
+
+// nonSeekableReader hides strings.Reader's Seek method so tests can force the
+// non-seekable path through MultipartBuilder.
+type nonSeekableReader struct {
+    io.Reader
+}
+
+func TestMultipartBuilderSetsContentLengthForSeekableParts(t *testing.T) {
+    var gotContentLength int64
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        gotContentLength = r.ContentLength
+        r.ParseMultipartForm(1 << 20)
+    }))
+    defer server.Close()
+
+    ct, body, err := MakeClient(server.URL).Multipart().
+        AddField("name", "example").
+        AddFile("upload", "hello.txt", strings.NewReader("hello multipart")).
+        Build()
+    if err != nil { t.Fatalf("Build failed: %v", err) }
+
+    sized, ok := body.(interface{ Size() int64 })
+    if !ok {
+        t.Fatalf("expected Build to report a Size() when all file parts are seekable, got %T", body)
+    }
+
+    client := MakeClient(server.URL).ContentType(ct)
+    if _, _, err := client.Stream("POST", body); err != nil {
+        t.Fatalf("Stream failed: %v", err)
+    }
+
+    if gotContentLength != sized.Size() {
+        t.Fatalf("expected request Content-Length %d to match Size() %d", gotContentLength, sized.Size())
+    }
+    if gotContentLength <= 0 {
+        t.Fatalf("expected a positive Content-Length, got %d", gotContentLength)
+    }
+}
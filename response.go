@@ -0,0 +1,37 @@
+package GoRest
+
+import "net/http"
+
+// Response wraps the *http.Response returned by Do together with its already-read body,
+// giving callers access to headers and status codes that Get/Put/Post/Delete otherwise
+// swallow.
+type Response struct {
+    *http.Response
+    body []byte
+}
+
+// StatusCode returns the response's HTTP status code.
+func (r *Response) StatusCode() int {
+    return r.Response.StatusCode
+}
+
+// IsSuccess reports whether the status code is in the 2xx range.
+func (r *Response) IsSuccess() bool {
+    code := r.StatusCode()
+    return code >= 200 && code < 300
+}
+
+// IsError reports whether the status code is outside the 2xx range.
+func (r *Response) IsError() bool {
+    return !r.IsSuccess()
+}
+
+// Bytes returns the raw response body.
+func (r *Response) Bytes() []byte {
+    return r.body
+}
+
+// String returns the raw response body as a string.
+func (r *Response) String() string {
+    return string(r.body)
+}
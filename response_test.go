@@ -0,0 +1,152 @@
+package GoRest
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func TestDeleteSendsRequestAndDecodesBody(t *testing.T) {
+    type result struct {
+        Deleted bool `json:"deleted"`
+    }
+
+    var gotMethod string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        gotMethod = r.Method
+        w.Header().Set("Content-Type", "application/json")
+        w.Write([]byte(`{"deleted":true}`))
+    }))
+    defer server.Close()
+
+    var out result
+    if err := MakeClient(server.URL).Delete(&out); err != nil {
+        t.Fatalf("Delete failed: %v", err)
+    }
+    if gotMethod != http.MethodDelete {
+        t.Fatalf("expected DELETE, got %s", gotMethod)
+    }
+    if !out.Deleted {
+        t.Fatalf("expected decoded body to report deleted=true, got %+v", out)
+    }
+}
+
+func TestPatchSendsBodyAndDecodesResponse(t *testing.T) {
+    type result struct {
+        OK bool `json:"ok"`
+    }
+
+    var gotMethod, gotBody string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        gotMethod = r.Method
+        buf := make([]byte, r.ContentLength)
+        r.Body.Read(buf)
+        gotBody = string(buf)
+        w.Header().Set("Content-Type", "application/json")
+        w.Write([]byte(`{"ok":true}`))
+    }))
+    defer server.Close()
+
+    var out result
+    if err := MakeClient(server.URL).Patch([]byte(`{"name":"x"}`), &out); err != nil {
+        t.Fatalf("Patch failed: %v", err)
+    }
+    if gotMethod != http.MethodPatch {
+        t.Fatalf("expected PATCH, got %s", gotMethod)
+    }
+    if gotBody != `{"name":"x"}` {
+        t.Fatalf("expected request body to be sent through, got %q", gotBody)
+    }
+    if !out.OK {
+        t.Fatalf("expected decoded body to report ok=true, got %+v", out)
+    }
+}
+
+func TestHeadReturnsResponseHeaders(t *testing.T) {
+    var gotMethod string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        gotMethod = r.Method
+        w.Header().Set("X-Custom", "yes")
+    }))
+    defer server.Close()
+
+    headers, err := MakeClient(server.URL).Head()
+    if err != nil {
+        t.Fatalf("Head failed: %v", err)
+    }
+    if gotMethod != http.MethodHead {
+        t.Fatalf("expected HEAD, got %s", gotMethod)
+    }
+    if headers.Get("X-Custom") != "yes" {
+        t.Fatalf("expected response headers to be returned, got %v", headers)
+    }
+}
+
+func TestOptionsReturnsResponseHeaders(t *testing.T) {
+    var gotMethod string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        gotMethod = r.Method
+        w.Header().Set("Allow", "GET, POST")
+    }))
+    defer server.Close()
+
+    headers, err := MakeClient(server.URL).Options()
+    if err != nil {
+        t.Fatalf("Options failed: %v", err)
+    }
+    if gotMethod != http.MethodOptions {
+        t.Fatalf("expected OPTIONS, got %s", gotMethod)
+    }
+    if headers.Get("Allow") != "GET, POST" {
+        t.Fatalf("expected response headers to be returned, got %v", headers)
+    }
+}
+
+func TestDoReturnsWiredResponse(t *testing.T) {
+    type result struct {
+        Name string `json:"name"`
+    }
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        w.Write([]byte(`{"name":"example"}`))
+    }))
+    defer server.Close()
+
+    var out result
+    res, err := MakeClient(server.URL).Do(http.MethodPost, []byte(`{}`), &out)
+    if err != nil {
+        t.Fatalf("Do failed: %v", err)
+    }
+    if res.StatusCode() != http.StatusCreated {
+        t.Fatalf("expected 201, got %d", res.StatusCode())
+    }
+    if !res.IsSuccess() || res.IsError() {
+        t.Fatalf("expected IsSuccess/IsError to reflect a 2xx status")
+    }
+    if res.String() != `{"name":"example"}` {
+        t.Fatalf("expected raw body to be preserved, got %q", res.String())
+    }
+    if out.Name != "example" {
+        t.Fatalf("expected decoded entity to be populated, got %+v", out)
+    }
+}
+
+func TestDoReportsErrorStatus(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusInternalServerError)
+    }))
+    defer server.Close()
+
+    res, err := MakeClient(server.URL).Do(http.MethodGet, nil)
+    if err != nil {
+        t.Fatalf("Do failed: %v", err)
+    }
+    if !res.IsError() || res.IsSuccess() {
+        t.Fatalf("expected IsError/IsSuccess to reflect a 5xx status")
+    }
+    if res.StatusCode() != http.StatusInternalServerError {
+        t.Fatalf("expected 500, got %d", res.StatusCode())
+    }
+}
@@ -1,22 +1,38 @@
 package GoRest
 import (
+    "context"
     "net/http"
     "fmt"
     "strings"
     u "net/url"
+    "io"
     "io/ioutil"
     "bytes"
     "errors"
+    "time"
 )
 
 type RestClient struct {
     client      *http.Client
+    ctx         context.Context
     url         string
     accept      MediaType
     contentType MediaType
     headers     map[string]string
     query       map[string]string
     cookies     []*http.Cookie
+    codecs      map[MediaType]Codec
+    auth        Authenticator
+    beforeMiddlewares []RequestMiddleware
+    afterMiddlewares  []ResponseMiddleware
+
+    retryCount         int
+    retryMinWait       time.Duration
+    retryMaxWait       time.Duration
+    retryMaxElapsed    time.Duration
+    retryBackoff       BackoffStrategy
+    retryCondition     func(*http.Response, error) bool
+    retryNonIdempotent bool
 }
 
 // The constructor for an immutable RestClient
@@ -27,27 +43,52 @@ type RestClient struct {
 // By providing the baseUrl to the RestClient it can be stored in a partial state to be built upon
 // in a route or handler function
 func MakeClient(baseUrl string) RestClient {
-    return newClient(
-            &http.Client{},
-            strings.Trim(baseUrl, "/"),
-            ApplicationJSON,
-            ApplicationJSON,
-            make(map[string]string),
-            make(map[string]string),
-            nil)
-}
-
-// Private constructor used to provide all RestClient parameters
-func newClient(client *http.Client, url string, accept MediaType, contentType MediaType, headers map[string]string,
-query map[string]string, cookies []*http.Cookie) RestClient {
     return RestClient{
-        client:         client,
-        url:            url,
-        accept:         accept,
-        contentType:    contentType,
-        headers:        headers,
-        query:          query,
-        cookies:        cookies}
+        client:         &http.Client{},
+        url:            strings.Trim(baseUrl, "/"),
+        accept:         ApplicationJSON,
+        contentType:    ApplicationJSON,
+        headers:        make(map[string]string),
+        query:          make(map[string]string),
+        cookies:        nil,
+        codecs:         defaultCodecs(),
+        retryMinWait:   100 * time.Millisecond,
+        retryMaxWait:   2 * time.Second,
+        retryBackoff:   ExponentialBackoff,
+        retryCondition: DefaultRetryCondition,
+    }
+}
+
+// clone returns a copy of rc with its own headers, query, and cookies slices so that
+// builder methods can mutate the copy without affecting rc or any other RestClient
+// derived from it.
+func (rc RestClient) clone() RestClient {
+    newHeaders := make(map[string]string, len(rc.headers))
+    for k, v := range rc.headers { newHeaders[k] = v }
+
+    newQuery := make(map[string]string, len(rc.query))
+    for k, v := range rc.query { newQuery[k] = v }
+
+    newCookies := make([]*http.Cookie, len(rc.cookies))
+    copy(newCookies, rc.cookies)
+
+    newCodecs := make(map[MediaType]Codec, len(rc.codecs))
+    for k, v := range rc.codecs { newCodecs[k] = v }
+
+    newBefore := make([]RequestMiddleware, len(rc.beforeMiddlewares))
+    copy(newBefore, rc.beforeMiddlewares)
+
+    newAfter := make([]ResponseMiddleware, len(rc.afterMiddlewares))
+    copy(newAfter, rc.afterMiddlewares)
+
+    newRc := rc
+    newRc.headers = newHeaders
+    newRc.query = newQuery
+    newRc.cookies = newCookies
+    newRc.codecs = newCodecs
+    newRc.beforeMiddlewares = newBefore
+    newRc.afterMiddlewares = newAfter
+    return newRc
 }
 
 // ===================================================================
@@ -75,97 +116,365 @@ func (rc RestClient) GetHeaders() map[string]string {
 // ===================================================================
 
 func (rc RestClient) Accept(accept MediaType) RestClient {
-    return newClient(rc.client, rc.url, accept, rc.contentType, rc.headers, rc.query, rc.cookies)
+    newRc := rc.clone()
+    newRc.accept = accept
+    return newRc
 }
 
 func (rc RestClient) ContentType(contentType MediaType) RestClient {
-    return newClient(rc.client, rc.url, rc.accept, contentType, rc.headers, rc.query, rc.cookies)
+    newRc := rc.clone()
+    newRc.contentType = contentType
+    return newRc
 }
 
 func (rc RestClient) Path(path ...string) RestClient {
-    newClient := newClient(rc.client, rc.url, rc.accept, rc.contentType, rc.headers, rc.query, rc.cookies)
-    for _, p := range path { newClient.url = fmt.Sprintf("%s/%s", newClient.url, strings.Trim(p, "/")) }
-    return newClient
+    newRc := rc.clone()
+    for _, p := range path { newRc.url = fmt.Sprintf("%s/%s", newRc.url, strings.Trim(p, "/")) }
+    return newRc
 }
 
 func (rc RestClient) Query(key, value string) RestClient {
-    newQuery := make(map[string]string)
-    for k, v := range rc.headers { newQuery[k] = v }
-    newQuery[key] = value
-    return newClient(rc.client, rc.url, rc.accept, rc.contentType, rc.headers, newQuery, rc.cookies)
+    newRc := rc.clone()
+    newRc.query[key] = value
+    return newRc
 }
 
 func (rc RestClient) Header(key, value string) RestClient {
-    newHeaders := make(map[string]string)
-    for k, v := range rc.headers { newHeaders[k] = v }
-    newHeaders[key] = value
-    return newClient(rc.client, rc.url, rc.accept, rc.contentType, newHeaders, rc.query, rc.cookies)
+    newRc := rc.clone()
+    newRc.headers[key] = value
+    return newRc
 }
 
 func (rc RestClient) Cookie(cookie *http.Cookie) RestClient {
-    return newClient(rc.client, rc.url, rc.accept, rc.contentType, rc.headers, rc.query, append(rc.cookies, cookie))
+    newRc := rc.clone()
+    newRc.cookies = append(newRc.cookies, cookie)
+    return newRc
+}
+
+// OnBeforeRequest appends middlewares to the chain run over the outgoing *http.Request,
+// after the built-in Accept/Content-Type/query/cookie middlewares and before auth is
+// applied. Middlewares run in the order they were registered and the chain stops at the
+// first error.
+func (rc RestClient) OnBeforeRequest(middlewares ...RequestMiddleware) RestClient {
+    newRc := rc.clone()
+    newRc.beforeMiddlewares = append(newRc.beforeMiddlewares, middlewares...)
+    return newRc
+}
+
+// OnAfterResponse appends middlewares to the chain run over the *http.Response once it is
+// received, after the built-in content-type validation. Middlewares run in the order they
+// were registered and the chain stops at the first error.
+func (rc RestClient) OnAfterResponse(middlewares ...ResponseMiddleware) RestClient {
+    newRc := rc.clone()
+    newRc.afterMiddlewares = append(newRc.afterMiddlewares, middlewares...)
+    return newRc
+}
+
+// Retry sets the number of times a request will be retried after a failed attempt.
+// A count of 0 (the default) disables retries.
+func (rc RestClient) Retry(count int) RestClient {
+    newRc := rc.clone()
+    newRc.retryCount = count
+    return newRc
+}
+
+// RetryWaitTime sets the minimum and maximum wait between retry attempts. These bound
+// the backoff strategy's computed wait on every attempt.
+func (rc RestClient) RetryWaitTime(min, max time.Duration) RestClient {
+    newRc := rc.clone()
+    newRc.retryMinWait = min
+    newRc.retryMaxWait = max
+    return newRc
+}
+
+// RetryBackoff overrides the strategy used to compute the wait before each retry attempt.
+func (rc RestClient) RetryBackoff(strategy BackoffStrategy) RestClient {
+    newRc := rc.clone()
+    newRc.retryBackoff = strategy
+    return newRc
+}
+
+// RetryOn overrides the predicate used to decide whether a response/error should be retried.
+// The default retries on connection errors and 429/503 responses.
+func (rc RestClient) RetryOn(condition func(*http.Response, error) bool) RestClient {
+    newRc := rc.clone()
+    newRc.retryCondition = condition
+    return newRc
+}
+
+// MaxElapsedTime caps the total time spent across all retry attempts, including waits.
+// Once exceeded, the next attempt is skipped and the last result is returned.
+func (rc RestClient) MaxElapsedTime(max time.Duration) RestClient {
+    newRc := rc.clone()
+    newRc.retryMaxElapsed = max
+    return newRc
+}
+
+// AllowNonIdempotentRetry opts a client into retrying POST/PUT requests, which are not
+// safe to retry blindly unless the caller knows the operation is idempotent.
+func (rc RestClient) AllowNonIdempotentRetry() RestClient {
+    newRc := rc.clone()
+    newRc.retryNonIdempotent = true
+    return newRc
+}
+
+// WithContext stores ctx on the client so that it is used by the non-Ctx verb methods
+// (Get, Put, Post, Delete) for cancellation, deadlines, and tracing.
+func (rc RestClient) WithContext(ctx context.Context) RestClient {
+    newRc := rc.clone()
+    newRc.ctx = ctx
+    return newRc
+}
+
+// context returns the context stored on rc via WithContext, or context.Background() if
+// none was set.
+func (rc RestClient) context() context.Context {
+    if rc.ctx != nil { return rc.ctx }
+    return context.Background()
 }
 
 func (rc RestClient) Get(resEntity ...interface{}) error {
-    return rc.request("GET", nil, resEntity...)
+    return rc.request(rc.context(), "GET", nil, resEntity...)
+}
+
+func (rc RestClient) GetCtx(ctx context.Context, resEntity ...interface{}) error {
+    return rc.request(ctx, "GET", nil, resEntity...)
 }
 
 func (rc RestClient) Put(reqBody []byte, resEntity ...interface{}) error {
-    return rc.request("PUT", reqBody, resEntity...)
+    return rc.request(rc.context(), "PUT", reqBody, resEntity...)
+}
+
+func (rc RestClient) PutCtx(ctx context.Context, reqBody []byte, resEntity ...interface{}) error {
+    return rc.request(ctx, "PUT", reqBody, resEntity...)
 }
 
 func (rc RestClient) Post(reqBody []byte, resEntity ...interface{}) error {
-    return rc.request("POST", reqBody, resEntity...)
+    return rc.request(rc.context(), "POST", reqBody, resEntity...)
 }
 
-func (rc RestClient) Delete(entity ...interface{}) error {
-    return nil
+func (rc RestClient) PostCtx(ctx context.Context, reqBody []byte, resEntity ...interface{}) error {
+    return rc.request(ctx, "POST", reqBody, resEntity...)
+}
+
+func (rc RestClient) Delete(resEntity ...interface{}) error {
+    return rc.request(rc.context(), "DELETE", nil, resEntity...)
+}
+
+func (rc RestClient) DeleteCtx(ctx context.Context, resEntity ...interface{}) error {
+    return rc.request(ctx, "DELETE", nil, resEntity...)
+}
+
+func (rc RestClient) Patch(reqBody []byte, resEntity ...interface{}) error {
+    return rc.request(rc.context(), "PATCH", reqBody, resEntity...)
+}
+
+func (rc RestClient) PatchCtx(ctx context.Context, reqBody []byte, resEntity ...interface{}) error {
+    return rc.request(ctx, "PATCH", reqBody, resEntity...)
+}
+
+// Head performs a HEAD request and returns the response headers.
+func (rc RestClient) Head() (http.Header, error) {
+    res, _, err := rc.doRequest(rc.context(), "HEAD", nil)
+    if res == nil { return nil, err }
+    return res.Header, err
+}
+
+// Options performs an OPTIONS request and returns the response headers.
+func (rc RestClient) Options() (http.Header, error) {
+    res, _, err := rc.doRequest(rc.context(), "OPTIONS", nil)
+    if res == nil { return nil, err }
+    return res.Header, err
+}
+
+// Do performs an arbitrary method, decoding the response into out via the accept codec
+// like the other verbs, but also returns the *Response so callers can inspect headers,
+// status code, and the raw body.
+func (rc RestClient) Do(method string, body []byte, out ...interface{}) (*Response, error) {
+    res, bodyBytes, err := rc.doRequest(rc.context(), method, body, out...)
+    if res == nil { return nil, err }
+    return &Response{Response: res, body: bodyBytes}, err
+}
+
+// isIdempotent reports whether httpReq is safe to retry without the caller opting in.
+func isIdempotent(httpReq string) bool {
+    switch httpReq {
+    case "GET", "HEAD", "OPTIONS", "DELETE":
+        return true
+    default:
+        return false
+    }
+}
+
+// bodyReader builds a fresh reader over reqBody for a single attempt. Retries must call
+// this again rather than reusing a previously-drained bytes.Buffer.
+func bodyReader(reqBody []byte) *bytes.Buffer {
+    if reqBody == nil { return nil }
+    return bytes.NewBuffer(reqBody)
 }
 
 // The main request function. This handles building out the request and reading the response into
 // the provided resEntity
-func (rc RestClient) request(httpReq string, reqBody []byte, resEntity ...interface{}) error {
+func (rc RestClient) request(ctx context.Context, httpReq string, reqBody []byte, resEntity ...interface{}) error {
+    _, _, err := rc.doRequest(ctx, httpReq, reqBody, resEntity...)
+    return err
+}
+
+// doRequest runs the retry loop (when enabled) around doAttempt, returning the final
+// *http.Response and its raw body bytes alongside any error.
+func (rc RestClient) doRequest(ctx context.Context, httpReq string, reqBody []byte, resEntity ...interface{}) (*http.Response, []byte, error) {
+    if rc.retryCount == 0 || (!isIdempotent(httpReq) && !rc.retryNonIdempotent) {
+        return rc.doAttempt(ctx, httpReq, reqBody, resEntity...)
+    }
+
+    start := time.Now()
+    var res *http.Response
+    var body []byte
+    var err error
+    for attempt := 0; attempt <= rc.retryCount; attempt++ {
+        res, body, err = rc.doAttempt(ctx, httpReq, reqBody, resEntity...)
+
+        if !rc.retryCondition(res, err) { return res, body, err }
+        if attempt == rc.retryCount { break }
+
+        wait := rc.retryBackoff(attempt, rc.retryMinWait, rc.retryMaxWait)
+        if res != nil {
+            if retryAfter, ok := parseRetryAfter(res); ok && retryAfter > wait { wait = retryAfter }
+        }
+        if rc.retryMaxElapsed > 0 && time.Since(start)+wait > rc.retryMaxElapsed { break }
+
+        select {
+        case <-ctx.Done():
+            return res, body, ctx.Err()
+        case <-time.After(wait):
+        }
+    }
+    return res, body, err
+}
+
+// defaultRequestMiddlewares returns the built-in before-request middlewares: Accept and
+// Content-Type headers, query params, and cookies. These always run first, ahead of any
+// middleware registered via OnBeforeRequest.
+func (rc RestClient) defaultRequestMiddlewares() []RequestMiddleware {
+    return []RequestMiddleware{
+        func(req *http.Request) error {
+            req.Header.Add("Accept", rc.accept.String())
+            req.Header.Add("Content-Type", rc.contentType.String())
+            return nil
+        },
+        func(req *http.Request) error {
+            q := req.URL.Query()
+            for k, v := range rc.query { q.Set(k, v) }
+            req.URL.RawQuery = q.Encode()
+            return nil
+        },
+        func(req *http.Request) error {
+            for _, c := range rc.cookies { req.AddCookie(c) }
+            return nil
+        },
+    }
+}
+
+// validateContentType is the built-in after-response middleware that rejects a response
+// whose Content-Type doesn't match the Accept type, since that would otherwise fail
+// unmarshalling with a more confusing error.
+func (rc RestClient) validateContentType(resEntity []interface{}) ResponseMiddleware {
+    return func(res *http.Response) error {
+        contentType := res.Header.Get("Content-Type")
+        if len(resEntity) != 0 &&
+        !strings.Contains(strings.ToLower(contentType), strings.ToLower(rc.accept.String())) {
+            return errors.New(fmt.Sprintf("Expected Response Content-Type [%s] to match/contain Request Accept [%s]",
+            contentType, rc.accept.String()))
+        }
+        return nil
+    }
+}
+
+// doAttempt performs a single request/response cycle, returning the raw *http.Response and
+// its body bytes (for retry decisions and for Do's *Response) alongside any error.
+func (rc RestClient) doAttempt(ctx context.Context, httpReq string, reqBody []byte, resEntity ...interface{}) (*http.Response, []byte, error) {
     // Validate the URL
     uri, err := u.Parse(rc.url);
-    if err != nil { return err }
+    if err != nil { return nil, nil, err }
 
-    // Add query params
-    for k, v := range rc.query { uri.Query().Add(k, v) }
+    beforeChain := append(rc.defaultRequestMiddlewares(), rc.beforeMiddlewares...)
 
-    var req *http.Request
+    // buildRequest is called once up front and, for a ChallengeAuthenticator like
+    // DigestAuth, a second time once the challenge from a 401 has been cached.
+    buildRequest := func() (*http.Request, error) {
+        var req *http.Request
+        var err error
+        if reqBody != nil {
+            req, err = http.NewRequestWithContext(ctx, httpReq, uri.String(), bodyReader(reqBody))
+        } else {
+            req, err = http.NewRequestWithContext(ctx, httpReq, uri.String(), nil)
+        }
+        if err != nil { return nil, err }
 
-    // Build the Request
-    if reqBody != nil {
-        req, err = http.NewRequest(httpReq, uri.String(), bytes.NewBuffer(reqBody))
-    } else {
-        req, err = http.NewRequest(httpReq, uri.String(), nil)
+        for _, mw := range beforeChain {
+            if err := mw(req); err != nil { return nil, err }
+        }
+
+        if rc.auth != nil {
+            if err := rc.auth.Apply(req); err != nil { return nil, err }
+        }
+        return req, nil
     }
-    if err != nil { return err }
 
-    // Add headers
-    req.Header.Add("Accept", rc.accept.String())
-    req.Header.Add("Content-Type", rc.contentType.String())
+    req, err := buildRequest()
+    if err != nil { return nil, nil, err }
 
     // Make Request
     res, err := rc.client.Do(req)
-    if err != nil { return err }
-
-    // Validate the response content type matches the accept type.
-    // This is required to allow unmarshalling to the resEntity
-    if contentType := res.Header.Get("Content-Type"); len(resEntity) != 0 &&
-    !strings.Contains(strings.ToLower(contentType), strings.ToLower(rc.accept.String())) {
-        return errors.New(fmt.Sprintf("Expected Response Content-Type [%s] to match/contain Request Accept [%s]",
-        contentType, rc.accept.String()))
+    if err != nil { return res, nil, err }
+
+    // Give a ChallengeAuthenticator (e.g. DigestAuth) a chance to observe a 401 and
+    // replay the request once it has credentials to answer the challenge. If Challenge
+    // doesn't recognize the challenge (handled == false), res is left untouched so the
+    // normal read path below can still return the real 401 to the caller.
+    if res.StatusCode == http.StatusUnauthorized {
+        if challenger, ok := rc.auth.(ChallengeAuthenticator); ok {
+            handled, cerr := challenger.Challenge(res)
+            if cerr != nil { return res, nil, cerr }
+
+            if handled {
+                io.Copy(ioutil.Discard, res.Body)
+                res.Body.Close()
+
+                retryReq, err := buildRequest()
+                if err != nil { return nil, nil, err }
+
+                res, err = rc.client.Do(retryReq)
+                if err != nil { return res, nil, err }
+            }
+        }
+    }
+
+    // Always drain and close the body so the connection can be reused, even when a
+    // cancelled context or content-type mismatch causes an early return below.
+    defer func() {
+        io.Copy(ioutil.Discard, res.Body)
+        res.Body.Close()
+    }()
+
+    afterChain := append([]ResponseMiddleware{rc.validateContentType(resEntity)}, rc.afterMiddlewares...)
+    for _, mw := range afterChain {
+        if err := mw(res); err != nil { return res, nil, err }
     }
 
     body, err := ioutil.ReadAll(res.Body)
-    if err != nil { return err }
+    if err != nil { return res, nil, err }
 
-    // If entities were passed in then unmarshal the body into each
-    for _, e := range resEntity {
-        if err = rc.accept.Unmarshal(body, e); err != nil { return err }
+    // If entities were passed in then decode the body into each via the accept codec
+    if len(resEntity) != 0 {
+        codec, err := rc.codecFor(rc.accept)
+        if err != nil { return res, body, err }
+
+        for _, e := range resEntity {
+            if err = codec.Decode(bytes.NewReader(body), e); err != nil { return res, body, err }
+        }
     }
 
     // Return success
-    return nil
-}
\ No newline at end of file
+    return res, body, nil
+}
@@ -0,0 +1,76 @@
+package GoRest
+
+import (
+    "errors"
+    "math/rand"
+    "net"
+    "net/http"
+    "strconv"
+    "time"
+)
+
+// BackoffStrategy computes how long to wait before the given retry attempt (0-indexed),
+// bounded by minWait and maxWait.
+type BackoffStrategy func(attempt int, minWait, maxWait time.Duration) time.Duration
+
+// ConstantBackoff always waits minWait between attempts.
+func ConstantBackoff(attempt int, minWait, maxWait time.Duration) time.Duration {
+    return minWait
+}
+
+// LinearBackoff waits minWait * (attempt + 1), capped at maxWait.
+func LinearBackoff(attempt int, minWait, maxWait time.Duration) time.Duration {
+    wait := minWait * time.Duration(attempt+1)
+    if wait > maxWait { return maxWait }
+    return wait
+}
+
+// ExponentialBackoff waits minWait * 2^attempt plus a random jitter up to minWait,
+// capped at maxWait. The doubling stops as soon as it reaches maxWait so a large attempt
+// count can't overflow the duration into a negative wait.
+func ExponentialBackoff(attempt int, minWait, maxWait time.Duration) time.Duration {
+    wait := minWait
+    for i := 0; i < attempt && wait < maxWait; i++ {
+        wait *= 2
+        if wait <= 0 { // overflowed past maxWait
+            wait = maxWait
+            break
+        }
+    }
+    if wait > maxWait { wait = maxWait }
+
+    jitter := time.Duration(rand.Int63n(int64(minWait) + 1))
+    if wait+jitter > maxWait || wait+jitter <= 0 { return maxWait }
+    return wait + jitter
+}
+
+// DefaultRetryCondition retries on transport errors (timeouts, connection failures) and on
+// 429/503 responses. It does not retry errors doAttempt returns for client-side problems
+// like a content-type mismatch or a decode failure, since those are permanent regardless
+// of how many times the request is replayed.
+func DefaultRetryCondition(res *http.Response, err error) bool {
+    if err != nil {
+        var netErr net.Error
+        return errors.As(err, &netErr)
+    }
+    return res != nil && (res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable)
+}
+
+// parseRetryAfter reads the Retry-After header off of res, supporting both the
+// delay-seconds and HTTP-date forms. The second return value is false when the
+// header is absent or unparsable.
+func parseRetryAfter(res *http.Response) (time.Duration, bool) {
+    value := res.Header.Get("Retry-After")
+    if value == "" { return 0, false }
+
+    if seconds, err := strconv.Atoi(value); err == nil {
+        return time.Duration(seconds) * time.Second, true
+    }
+
+    if when, err := http.ParseTime(value); err == nil {
+        if wait := time.Until(when); wait > 0 { return wait, true }
+        return 0, true
+    }
+
+    return 0, false
+}
@@ -0,0 +1,132 @@
+package GoRest
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestParseRetryAfterSeconds(t *testing.T) {
+    res := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+
+    wait, ok := parseRetryAfter(res)
+    if !ok { t.Fatal("expected Retry-After to be parsed") }
+    if wait != 2*time.Second { t.Fatalf("expected 2s, got %v", wait) }
+}
+
+func TestParseRetryAfterHTTPDate(t *testing.T) {
+    when := time.Now().Add(3 * time.Second).Truncate(time.Second)
+    res := &http.Response{Header: http.Header{"Retry-After": []string{when.UTC().Format(http.TimeFormat)}}}
+
+    wait, ok := parseRetryAfter(res)
+    if !ok { t.Fatal("expected Retry-After to be parsed") }
+    if wait <= 0 || wait > 3*time.Second+time.Second { t.Fatalf("expected ~3s, got %v", wait) }
+}
+
+func TestParseRetryAfterAbsent(t *testing.T) {
+    res := &http.Response{Header: http.Header{}}
+
+    if _, ok := parseRetryAfter(res); ok {
+        t.Fatal("expected no Retry-After to be reported as absent")
+    }
+}
+
+func TestExponentialBackoffDoesNotOverflow(t *testing.T) {
+    minWait := 100 * time.Millisecond
+    maxWait := 2 * time.Second
+
+    for _, attempt := range []int{0, 1, 5, 10, 40, 1000} {
+        wait := ExponentialBackoff(attempt, minWait, maxWait)
+        if wait <= 0 {
+            t.Fatalf("attempt %d: wait must be positive, got %v", attempt, wait)
+        }
+        if wait > maxWait {
+            t.Fatalf("attempt %d: wait %v exceeds maxWait %v", attempt, wait, maxWait)
+        }
+    }
+}
+
+func TestDefaultRetryConditionIgnoresClientSideErrors(t *testing.T) {
+    // A content-type mismatch / decode error is a permanent client-side problem, not a
+    // transient transport failure, so it must not be retried.
+    if DefaultRetryCondition(nil, errContentTypeMismatch("boom")) {
+        t.Fatal("expected a plain error to not be retried")
+    }
+}
+
+type errContentTypeMismatch string
+
+func (e errContentTypeMismatch) Error() string { return string(e) }
+
+func TestDefaultRetryConditionRetriesOnStatus(t *testing.T) {
+    res := &http.Response{StatusCode: http.StatusServiceUnavailable}
+    if !DefaultRetryCondition(res, nil) {
+        t.Fatal("expected 503 to be retried")
+    }
+
+    res = &http.Response{StatusCode: http.StatusOK}
+    if DefaultRetryCondition(res, nil) {
+        t.Fatal("expected 200 to not be retried")
+    }
+}
+
+func TestMaxElapsedTimeStopsRetrying(t *testing.T) {
+    var attempts int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        atomic.AddInt32(&attempts, 1)
+        w.WriteHeader(http.StatusServiceUnavailable)
+    }))
+    defer server.Close()
+
+    client := MakeClient(server.URL).
+        Retry(100).
+        RetryWaitTime(50*time.Millisecond, 50*time.Millisecond).
+        MaxElapsedTime(200 * time.Millisecond)
+
+    start := time.Now()
+    client.Get()
+    elapsed := time.Since(start)
+
+    if elapsed > 2*time.Second {
+        t.Fatalf("expected MaxElapsedTime to bound retries, took %v", elapsed)
+    }
+    if atomic.LoadInt32(&attempts) >= 100 {
+        t.Fatalf("expected MaxElapsedTime to cut off retries well before the retry count, got %d attempts", attempts)
+    }
+}
+
+func TestRetryReplaysBodyOnEachAttempt(t *testing.T) {
+    const wantBody = `{"hello":"world"}`
+
+    var attempts int32
+    var mismatched int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        buf := make([]byte, len(wantBody))
+        n, _ := r.Body.Read(buf)
+        if string(buf[:n]) != wantBody { atomic.AddInt32(&mismatched, 1) }
+
+        if atomic.AddInt32(&attempts, 1) < 3 {
+            w.WriteHeader(http.StatusServiceUnavailable)
+            return
+        }
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer server.Close()
+
+    client := MakeClient(server.URL).
+        Retry(3).
+        AllowNonIdempotentRetry().
+        RetryWaitTime(time.Millisecond, time.Millisecond)
+
+    if err := client.Put([]byte(wantBody)); err != nil {
+        t.Fatalf("expected eventual success, got %v", err)
+    }
+    if atomic.LoadInt32(&attempts) != 3 {
+        t.Fatalf("expected 3 attempts, got %d", attempts)
+    }
+    if atomic.LoadInt32(&mismatched) != 0 {
+        t.Fatalf("expected the body to be replayed identically on every attempt")
+    }
+}
@@ -0,0 +1,57 @@
+package GoRest
+
+import (
+    "fmt"
+    "io"
+    "net/http"
+    u "net/url"
+)
+
+// sizer is implemented by body readers (e.g. MultipartBuilder.Build's streamed body
+// when every file part is seekable) that know their encoded length up front without being
+// fully read.
+type sizer interface {
+    Size() int64
+}
+
+// Stream sends method with body streamed directly from the reader and returns the
+// response body unread, so callers can pull large downloads or server-sent events
+// without buffering them into memory. The caller is responsible for closing the returned
+// io.ReadCloser. Unlike Get/Post/Put, Stream does not retry: an io.Reader body generally
+// can't be replayed across attempts.
+func (rc RestClient) Stream(method string, body io.Reader) (io.ReadCloser, *http.Response, error) {
+    uri, err := u.Parse(rc.url)
+    if err != nil { return nil, nil, err }
+
+    req, err := http.NewRequestWithContext(rc.context(), method, uri.String(), body)
+    if err != nil { return nil, nil, err }
+
+    if s, ok := body.(sizer); ok {
+        req.ContentLength = s.Size()
+    }
+
+    for _, mw := range append(rc.defaultRequestMiddlewares(), rc.beforeMiddlewares...) {
+        if err := mw(req); err != nil { return nil, nil, err }
+    }
+    if rc.auth != nil {
+        if err := rc.auth.Apply(req); err != nil { return nil, nil, err }
+    }
+
+    res, err := rc.client.Do(req)
+    if err != nil { return nil, res, err }
+    return res.Body, res, nil
+}
+
+// Download GETs the client's URL and copies the response body into dest as it arrives,
+// returning the number of bytes written.
+func (rc RestClient) Download(dest io.Writer) (int64, error) {
+    body, res, err := rc.Stream("GET", nil)
+    if err != nil { return 0, err }
+    defer body.Close()
+
+    if res.StatusCode >= 400 {
+        return 0, fmt.Errorf("download failed with status %d", res.StatusCode)
+    }
+
+    return io.Copy(dest, body)
+}